@@ -1,22 +1,28 @@
 package microenv
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type Awaiter struct {
 	mu   sync.Mutex
 	done bool
 	val  interface{}
-	chs  []chan interface{}
+	chs  map[chan interface{}]struct{}
 }
 
 func newAwaiter() *Awaiter {
-	return &Awaiter{chs: make([]chan interface{}, 0)}
+	return &Awaiter{chs: make(map[chan interface{}]struct{})}
 }
 
-func (w *Awaiter) addWaiter() <-chan interface{} {
+// addWaiter registers a new waiter channel and returns it. The returned
+// channel is also usable as a handle for removeWaiter.
+func (w *Awaiter) addWaiter() chan interface{} {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	ch := make(chan interface{}, 1)
@@ -25,10 +31,26 @@ func (w *Awaiter) addWaiter() <-chan interface{} {
 		close(ch)
 		return ch
 	}
-	w.chs = append(w.chs, ch)
+	w.chs[ch] = struct{}{}
 	return ch
 }
 
+// removeWaiter drops ch from the waiter set, e.g. on caller cancellation.
+// It reports whether ch was still pending and, if so, how many waiters
+// remain so the caller can decide whether the Awaiter is now empty.
+func (w *Awaiter) removeWaiter(ch chan interface{}) (remaining int, existed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return len(w.chs), false
+	}
+	if _, ok := w.chs[ch]; ok {
+		delete(w.chs, ch)
+		existed = true
+	}
+	return len(w.chs), existed
+}
+
 func (w *Awaiter) resolve(val interface{}) {
 	w.mu.Lock()
 	if w.done {
@@ -37,7 +59,7 @@ func (w *Awaiter) resolve(val interface{}) {
 	}
 	w.done = true
 	w.val = val
-	for _, ch := range w.chs {
+	for ch := range w.chs {
 		ch <- val
 		close(ch)
 	}
@@ -58,8 +80,27 @@ type MicroEnv struct {
 	face             map[string]*FacePropertyAPI // fixed at startup
 	customDescriptor map[string]interface{}
 
-	// NEW: private property support
-	privateFlags map[string]bool // key: property name, value: isPrivate
+	// Per-property access control, keyed by property name. A key absent
+	// from acl is fully public for every op.
+	acl         map[string]*accessRule
+	callerRoles func(caller string) []string
+
+	// Watch/subscription support
+	watchBuf  int
+	nextSubID uint64
+	keySubs   sync.Map // map[string]*sync.Map (subID -> *subscription)
+	allSubs   sync.Map // map[uint64]*subscription, fed by WatchAll
+
+	// callPlans caches the validated call shape for each registered
+	// function, keyed by property name, so Call/CallErr only inspects a
+	// function's reflect.Type once per (key, underlying function type).
+	callPlans sync.Map // map[string]*callPlan
+
+	// facadeCache is scratch space for typed-facade packages built on top
+	// of MicroEnv (e.g. "typed") to memoize their own derived reflect
+	// information, scoped to this instance's lifetime. microenv itself
+	// never reads or writes it; see FacadeCache.
+	facadeCache sync.Map
 }
 
 type MicroEnvOption func(*MicroEnv)
@@ -72,6 +113,115 @@ func WithCustomDescriptor(desc map[string]interface{}) MicroEnvOption {
 	}
 }
 
+// WithWatchBuffer sets the per-subscription buffer size used by Watch and
+// WatchAll. When a subscriber falls behind and its buffer fills up, the
+// oldest queued event is dropped to make room for the new one (see
+// Subscription.Dropped).
+func WithWatchBuffer(n int) MicroEnvOption {
+	return func(m *MicroEnv) { m.watchBuf = n }
+}
+
+// WithCallerRoles lets the env resolve a caller string to a set of roles
+// (e.g. "role:admin") at access-check time, so ACL entries can grant
+// access by role instead of listing every caller individually.
+func WithCallerRoles(roles func(caller string) []string) MicroEnvOption {
+	return func(m *MicroEnv) { m.callerRoles = roles }
+}
+
+const defaultWatchBuffer = 16
+
+// AccessOp identifies which kind of access an ACL entry governs.
+type AccessOp string
+
+const (
+	OpRead  AccessOp = "read"
+	OpWrite AccessOp = "write"
+	OpCall  AccessOp = "call"
+)
+
+// accessRule is the parsed form of a descriptor entry's "access" object:
+// per-op lists of caller specs. An op absent from the map is unrestricted.
+type accessRule map[AccessOp][]string
+
+// ownerSpec is the backward-compatible stand-in for the old boolean
+// private flag: it grants access only to the owner caller (the empty
+// string), same as the pre-ACL isAllowedKey did.
+const ownerSpec = "owner"
+
+// parseAccessRule reads a descriptor entry's "access" field, falling back
+// to the legacy boolean "private" field (mapped to owner-only read/write/
+// call) when "access" isn't present. Returns nil (fully public) if neither
+// is set.
+func parseAccessRule(entry map[string]interface{}) *accessRule {
+	if raw, ok := entry["access"].(map[string]interface{}); ok {
+		rule := accessRule{}
+		for _, op := range []AccessOp{OpRead, OpWrite, OpCall} {
+			specsRaw, ok := raw[string(op)]
+			if !ok {
+				continue
+			}
+			specs, ok := specsRaw.([]string)
+			if !ok {
+				// Also accept []interface{} of strings, the shape JSON
+				// unmarshaling into map[string]interface{} produces.
+				if list, ok := specsRaw.([]interface{}); ok {
+					specs = make([]string, 0, len(list))
+					for _, v := range list {
+						if s, ok := v.(string); ok {
+							specs = append(specs, s)
+						}
+					}
+				}
+			}
+			rule[op] = specs
+		}
+		return &rule
+	}
+	if priv, ok := entry["private"].(bool); ok && priv {
+		return &accessRule{
+			OpRead:  {ownerSpec},
+			OpWrite: {ownerSpec},
+			OpCall:  {ownerSpec},
+		}
+	}
+	return nil
+}
+
+// matchesSpecs reports whether caller satisfies any of specs: a literal
+// caller match, "*" (anyone), "owner" (the empty-string caller, matching
+// the pre-ACL private semantics), or "role:X" (resolved via
+// WithCallerRoles).
+func (m *MicroEnv) matchesSpecs(specs []string, caller string) bool {
+	var roles []string
+	rolesLoaded := false
+	for _, spec := range specs {
+		switch {
+		case spec == "*":
+			return true
+		case spec == ownerSpec:
+			if caller == "" {
+				return true
+			}
+		case strings.HasPrefix(spec, "role:"):
+			if !rolesLoaded {
+				if m.callerRoles != nil {
+					roles = m.callerRoles(caller)
+				}
+				rolesLoaded = true
+			}
+			want := strings.TrimPrefix(spec, "role:")
+			for _, r := range roles {
+				if r == want {
+					return true
+				}
+			}
+		case spec == caller:
+			return true
+		}
+	}
+	return false
+}
+
 type FacePropertyAPI struct {
 	Get func(caller string) (interface{}, bool)
 	Set func(val interface{}, caller string)
@@ -79,8 +229,9 @@ type FacePropertyAPI struct {
 
 func NewMicroEnv(data map[string]interface{}, opts ...MicroEnvOption) *MicroEnv {
 	m := &MicroEnv{
-		face:         make(map[string]*FacePropertyAPI),
-		privateFlags: make(map[string]bool), // << NEW
+		face:     make(map[string]*FacePropertyAPI),
+		acl:      make(map[string]*accessRule),
+		watchBuf: defaultWatchBuffer,
 	}
 	for k, v := range data {
 		m.data.Store(k, v)
@@ -88,15 +239,15 @@ func NewMicroEnv(data map[string]interface{}, opts ...MicroEnvOption) *MicroEnv
 	for _, opt := range opts {
 		opt(m)
 	}
-	// Build face map from descriptor and setup private flag map:
+	// Build face map from descriptor and resolve each property's ACL:
 	desc := m.Descriptor()
 	if children, ok := desc["children"].([]map[string]interface{}); ok {
 		for _, entry := range children {
 			key := entry["key"].(string)
 			// Copy closure
 			k := key
-			if priv, ok := entry["private"].(bool); ok && priv {
-				m.privateFlags[key] = true
+			if rule := parseAccessRule(entry); rule != nil {
+				m.acl[key] = rule
 			}
 			m.face[k] = &FacePropertyAPI{
 				Get: func(caller string) (interface{}, bool) {
@@ -112,19 +263,40 @@ func NewMicroEnv(data map[string]interface{}, opts ...MicroEnvOption) *MicroEnv
 	return m
 }
 
-// Helper: allow access only to descriptor/face keys, with private/caller logic
-func (m *MicroEnv) isAllowedKey(key string, caller string) bool {
+// isAllowed reports whether caller may perform op (read/write/call) on
+// key. A key outside the descriptor/face is never allowed; a key with no
+// ACL entry is fully public; otherwise the op's spec list (see
+// matchesSpecs) decides. An op missing from an otherwise-present ACL
+// entry is unrestricted.
+func (m *MicroEnv) isAllowed(key string, op AccessOp, caller string) bool {
 	if _, exists := m.face[key]; !exists {
 		return false
 	}
-	if !m.privateFlags[key] {
+	rule, ok := m.acl[key]
+	if !ok {
+		return true
+	}
+	specs, specified := (*rule)[op]
+	if !specified {
 		return true
 	}
-	return caller == "" // only 'owner' (empty string) can access private
+	return m.matchesSpecs(specs, caller)
 }
 
+// Get is a thin wrapper around GetCtx using context.Background(), i.e. the
+// returned awaiter channel (when next is true) can never be cancelled early.
 func (m *MicroEnv) Get(key string, next bool, caller string) (interface{}, <-chan interface{}, bool) {
-	if !m.isAllowedKey(key, caller) {
+	return m.GetCtx(context.Background(), key, next, caller)
+}
+
+// GetCtx behaves like Get, except that when next is true the returned
+// channel is tied to ctx: cancelling ctx closes the channel without ever
+// sending a value, and removes this specific waiter from the underlying
+// Awaiter so it doesn't leak. If this was the last pending waiter, the
+// Awaiter itself is removed from MicroEnv.awaiters so a later
+// Get(key, true, ...) starts fresh instead of joining a dead entry.
+func (m *MicroEnv) GetCtx(ctx context.Context, key string, next bool, caller string) (interface{}, <-chan interface{}, bool) {
+	if !m.isAllowed(key, OpRead, caller) {
 		return nil, nil, false
 	}
 	if !next {
@@ -137,13 +309,42 @@ func (m *MicroEnv) Get(key string, next bool, caller string) (interface{}, <-cha
 	}
 	awRaw, _ := m.awaiters.LoadOrStore(key, newAwaiter())
 	aw := awRaw.(*Awaiter)
-	return nil, aw.addWaiter(), true
+	ch := aw.addWaiter()
+	if ctx == nil || ctx.Done() == nil {
+		return nil, ch, true
+	}
+
+	out := make(chan interface{}, 1)
+	go func() {
+		defer close(out)
+		select {
+		case val, ok := <-ch:
+			if ok {
+				out <- val
+			}
+		case <-ctx.Done():
+			remaining, existed := aw.removeWaiter(ch)
+			if !existed {
+				// Resolved concurrently with the cancellation; take the
+				// value that's already waiting for us instead of dropping it.
+				if val, ok := <-ch; ok {
+					out <- val
+				}
+				return
+			}
+			if remaining == 0 {
+				m.awaiters.CompareAndDelete(key, aw)
+			}
+		}
+	}()
+	return nil, out, true
 }
 
 func (m *MicroEnv) Set(key string, val interface{}, caller string) {
-	if !m.isAllowedKey(key, caller) {
+	if !m.isAllowed(key, OpWrite, caller) {
 		return
 	}
+	old, _ := m.data.Load(key)
 	m.data.Store(key, val)
 	if m.customSet != nil {
 		m.customSet(key, val, &m.data, caller)
@@ -152,39 +353,397 @@ func (m *MicroEnv) Set(key string, val interface{}, caller string) {
 		aw.(*Awaiter).resolve(val)
 		m.awaiters.Delete(key)
 	}
+	m.publish(key, old, val, caller)
+}
+
+// callShape is how many of (payload, data, caller) a registered function
+// takes, in that fixed order (optionally preceded by a context.Context).
+type callShape int
+
+const (
+	shapePayload callShape = iota // func([ctx,] payload) R...
+	shapeData                     // func([ctx,] payload, *sync.Map) R...
+	shapeFull                     // func([ctx,] payload, *sync.Map, string) R...
+)
+
+var (
+	ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// callPlan is the validated, cacheable shape of a function registered for
+// Call/CallErr: whether it takes a leading context.Context, how many of
+// (payload, data, caller) it wants, whether its payload parameter is
+// variadic, and whether its last return value is an error.
+type callPlan struct {
+	fnType   reflect.Type
+	hasCtx   bool
+	shape    callShape
+	variadic bool
+	lastErr  bool
+}
+
+// payloadType returns the type of fnType's payload parameter - the slice
+// type itself for a variadic payload, since that's what CallSlice expects.
+func (p *callPlan) payloadType() reflect.Type {
+	idx := 0
+	if p.hasCtx {
+		idx = 1
+	}
+	return p.fnType.In(idx)
+}
+
+// buildCallPlan inspects t and returns the callPlan for it, or a
+// descriptive error if t doesn't match any of the supported shapes:
+// func(payload) R..., func(payload, *sync.Map) R...,
+// func(payload, *sync.Map, string) R..., each optionally preceded by a
+// leading context.Context, and each optionally variadic in payload alone
+// (Go requires a variadic parameter to be the function's last, so a
+// variadic payload can only be used in the payload-only shape).
+func buildCallPlan(t reflect.Type) (*callPlan, error) {
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, fmt.Errorf("microenv: not a function")
+	}
+	in := t.NumIn()
+	hasCtx := in > 0 && t.In(0) == ctxType
+	start := 0
+	if hasCtx {
+		start = 1
+	}
+	nargs := in - start
+	if nargs < 1 || nargs > 3 {
+		return nil, fmt.Errorf("microenv: unsupported signature %s: want (payload[, *sync.Map[, string]]), optionally context.Context-first", t)
+	}
+	shape := callShape(nargs - 1)
+	if t.IsVariadic() && shape != shapePayload {
+		return nil, fmt.Errorf("microenv: unsupported signature %s: a variadic parameter must be the function's only payload argument", t)
+	}
+	if t.NumOut() == 0 {
+		return nil, fmt.Errorf("microenv: unsupported signature %s: must return at least one value", t)
+	}
+	lastErr := t.Out(t.NumOut()-1) == errType
+	return &callPlan{fnType: t, hasCtx: hasCtx, shape: shape, variadic: t.IsVariadic(), lastErr: lastErr}, nil
+}
+
+// planFor returns the cached callPlan for key if it's still valid for fn's
+// current type, otherwise builds and caches a fresh one.
+func (m *MicroEnv) planFor(key string, fn reflect.Value) (*callPlan, error) {
+	typ := fn.Type()
+	if cached, ok := m.callPlans.Load(key); ok {
+		if p := cached.(*callPlan); p.fnType == typ {
+			return p, nil
+		}
+	}
+	plan, err := buildCallPlan(typ)
+	if err != nil {
+		return nil, err
+	}
+	m.callPlans.Store(key, plan)
+	return plan, nil
+}
+
+// invoke dispatches fn according to plan, passing payload, &m.data and
+// caller in whichever prefix plan.shape calls for, splatting a slice
+// payload across a variadic parameter via CallSlice.
+func (m *MicroEnv) invoke(plan *callPlan, fn reflect.Value, payload interface{}, caller string) []interface{} {
+	typ := plan.fnType
+	args := make([]reflect.Value, 0, typ.NumIn())
+	idx := 0
+	if plan.hasCtx {
+		args = append(args, reflect.ValueOf(context.Background()))
+		idx++
+	}
+	args = append(args, argValue(payload, typ.In(idx)))
+	idx++
+	if plan.shape >= shapeData {
+		args = append(args, reflect.ValueOf(&m.data))
+		idx++
+	}
+	if plan.shape >= shapeFull {
+		args = append(args, reflect.ValueOf(caller))
+		idx++
+	}
+	var results []reflect.Value
+	if plan.variadic {
+		results = fn.CallSlice(args)
+	} else {
+		results = fn.Call(args)
+	}
+	out := make([]interface{}, len(results))
+	for i := range results {
+		out[i] = results[i].Interface()
+	}
+	return out
+}
+
+// argValue turns a Call argument into a reflect.Value for a parameter of
+// type want, substituting the zero value for a nil payload instead of
+// letting reflect.ValueOf(nil) panic.
+func argValue(v interface{}, want reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(want)
+	}
+	return reflect.ValueOf(v)
+}
+
+// payloadAssignable reports whether v can be passed as an argument of type
+// want, the same check reflect.Value.Call would make, but done up front so
+// a mismatched payload (e.g. a string where a registered func wants
+// []int) fails as an ordinary (nil, nil, false) CallErr result instead of
+// panicking inside reflect. A nil payload is always fine: argValue zero-
+// values it.
+func payloadAssignable(v interface{}, want reflect.Type) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.TypeOf(v).AssignableTo(want)
+}
+
+// callDispatch is the shared implementation behind Call and CallErr: it
+// checks call access, loads and validates the function at key, invokes
+// it, and - if its last return value is an error - splits that off. When
+// splitErr is true, the trailing error is also dropped from the returned
+// slice, for CallErr; Call passes splitErr false and keeps it in place.
+func (m *MicroEnv) callDispatch(key string, payload interface{}, caller string, splitErr bool) ([]interface{}, error, bool) {
+	if !m.isAllowed(key, OpCall, caller) {
+		return nil, nil, false
+	}
+	valRaw, ok := m.data.Load(key)
+	if !ok {
+		return nil, nil, false
+	}
+	fn := reflect.ValueOf(valRaw)
+	if fn.Kind() != reflect.Func {
+		return nil, nil, false
+	}
+	plan, err := m.planFor(key, fn)
+	if err != nil {
+		return nil, nil, false
+	}
+	if !payloadAssignable(payload, plan.payloadType()) {
+		return nil, nil, false
+	}
+	results := m.invoke(plan, fn, payload, caller)
+	var callErr error
+	if plan.lastErr && len(results) > 0 {
+		callErr, _ = results[len(results)-1].(error)
+		if splitErr {
+			results = results[:len(results)-1]
+		}
+	}
+	return results, callErr, true
 }
 
+// Call invokes the function stored at key with payload, same as before:
+// it accepts any of the shapes documented on buildCallPlan, validating
+// and caching the shape the first time a given key/function is seen. If
+// the function's last return value is an error, use CallErr to observe
+// it; Call folds it back into the result slice like any other value.
 func (m *MicroEnv) Call(key string, payload interface{}, caller string) ([]interface{}, bool) {
-	if !m.isAllowedKey(key, caller) {
-		return nil, false
+	results, _, ok := m.callDispatch(key, payload, caller, false)
+	return results, ok
+}
+
+// CallErr is Call, but splits a trailing error return out of the result
+// slice instead of leaving it there.
+func (m *MicroEnv) CallErr(key string, payload interface{}, caller string) ([]interface{}, error, bool) {
+	return m.callDispatch(key, payload, caller, true)
+}
+
+// RegisterFunc validates fn's signature against the shapes Call supports
+// and, if valid, stores it at key and pre-caches its call plan - so a bad
+// registration fails loudly here instead of silently at the first Call.
+// Like Set, key must already be a declared property (i.e. present in the
+// data/descriptor NewMicroEnv was built with): the face map is fixed at
+// startup, so RegisterFunc can't introduce a brand new property.
+func (m *MicroEnv) RegisterFunc(key string, fn interface{}) error {
+	if _, exists := m.face[key]; !exists {
+		return fmt.Errorf("microenv: RegisterFunc(%q): not a declared property", key)
+	}
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func {
+		return fmt.Errorf("microenv: RegisterFunc(%q): not a function", key)
+	}
+	plan, err := buildCallPlan(typ)
+	if err != nil {
+		return fmt.Errorf("microenv: RegisterFunc(%q): %w", key, err)
 	}
+	m.data.Store(key, fn)
+	m.callPlans.Store(key, plan)
+	return nil
+}
+
+// Signature returns the reflect.Type of the function stored at key, so a
+// caller can validate or cache its shape (arg/return types) up front
+// instead of re-inspecting it on every Call. It reports false if key isn't
+// set or isn't a function.
+func (m *MicroEnv) Signature(key string) (reflect.Type, bool) {
 	valRaw, ok := m.data.Load(key)
 	if !ok {
 		return nil, false
 	}
-	val := reflect.ValueOf(valRaw)
-	if val.Kind() != reflect.Func {
+	t := reflect.TypeOf(valRaw)
+	if t == nil || t.Kind() != reflect.Func {
 		return nil, false
 	}
-	typ := val.Type()
-	if typ.NumIn() != 3 {
-		return nil, false
+	return t, true
+}
+
+// FacadeCache returns a *sync.Map scoped to m's lifetime, for a
+// typed-facade package built on MicroEnv to memoize its own per-key
+// derived state (e.g. a verified reflect.Type) without keeping a
+// package-level cache keyed by *MicroEnv around - which would both leak
+// every MicroEnv ever seen and never notice a key's function being
+// swapped for an incompatible one. Callers should store enough to detect
+// that themselves, the way callPlan's fnType does for Call/CallErr.
+func (m *MicroEnv) FacadeCache() *sync.Map {
+	return &m.facadeCache
+}
+
+// WatchEvent describes a single Set on a watched key.
+type WatchEvent struct {
+	Key      string
+	OldValue interface{}
+	NewValue interface{}
+	Caller   string
+}
+
+// Subscription is a live, persistent view onto a key (or, via WatchAll,
+// every key). Unlike the one-shot Awaiter returned by Get(..., next=true),
+// a Subscription keeps delivering events until Close is called.
+type Subscription interface {
+	// Events returns the channel that WatchEvents are delivered on. It is
+	// closed once Close is called.
+	Events() <-chan WatchEvent
+	// Dropped reports how many events were discarded because the consumer
+	// wasn't keeping up with the buffer (see WithWatchBuffer).
+	Dropped() uint64
+	// Close unregisters the subscription and closes its Events channel.
+	Close()
+}
+
+// subscription is the concrete Subscription implementation shared by
+// Watch and WatchAll.
+type subscription struct {
+	id     uint64
+	key    string // "" for a WatchAll subscription
+	caller string
+	env    *MicroEnv
+
+	mu      sync.Mutex
+	closed  bool
+	events  chan WatchEvent
+	dropped uint64
+}
+
+func (s *subscription) Events() <-chan WatchEvent { return s.events }
+
+func (s *subscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+func (s *subscription) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
 	}
-	// Prepare args with caller as string
-	args := make([]reflect.Value, 3)
-	for i, v := range []interface{}{payload, &m.data, caller} {
-		if v == nil {
-			args[i] = reflect.Zero(typ.In(i))
-		} else {
-			args[i] = reflect.ValueOf(v)
+	s.closed = true
+	s.mu.Unlock()
+	s.env.removeSubscription(s)
+	close(s.events)
+}
+
+// send delivers evt without ever blocking the caller (i.e. Set): if the
+// buffer is full, the oldest queued event is dropped to make room.
+func (s *subscription) send(evt WatchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for {
+		select {
+		case s.events <- evt:
+			return
+		default:
+		}
+		select {
+		case <-s.events:
+			s.dropped++
+		default:
+			return
 		}
 	}
-	results := val.Call(args)
-	res := make([]interface{}, len(results))
-	for i := range results {
-		res[i] = results[i].Interface()
+}
+
+func (m *MicroEnv) newSubscription(key, caller string) *subscription {
+	buf := m.watchBuf
+	if buf <= 0 {
+		buf = defaultWatchBuffer
+	}
+	return &subscription{
+		id:     atomic.AddUint64(&m.nextSubID, 1),
+		key:    key,
+		caller: caller,
+		env:    m,
+		events: make(chan WatchEvent, buf),
+	}
+}
+
+func (m *MicroEnv) removeSubscription(s *subscription) {
+	if s.key == "" {
+		m.allSubs.Delete(s.id)
+		return
+	}
+	if subsRaw, ok := m.keySubs.Load(s.key); ok {
+		subsRaw.(*sync.Map).Delete(s.id)
+	}
+}
+
+// Watch subscribes caller to every future Set on key, respecting the same
+// read access rules as Get. The returned Subscription must be Closed once
+// the caller is done to avoid leaking the buffered channel.
+func (m *MicroEnv) Watch(key string, caller string) (Subscription, bool) {
+	if !m.isAllowed(key, OpRead, caller) {
+		return nil, false
+	}
+	sub := m.newSubscription(key, caller)
+	subsRaw, _ := m.keySubs.LoadOrStore(key, &sync.Map{})
+	subsRaw.(*sync.Map).Store(sub.id, sub)
+	return sub, true
+}
+
+// WatchAll subscribes caller to every key Set on this MicroEnv. Events for
+// keys the caller isn't allowed to read are silently skipped, same as Watch.
+func (m *MicroEnv) WatchAll(caller string) Subscription {
+	sub := m.newSubscription("", caller)
+	m.allSubs.Store(sub.id, sub)
+	return sub
+}
+
+// publish fans out a Set to every live subscription for key, in addition
+// to WatchAll subscribers, respecting each subscriber's own read access.
+func (m *MicroEnv) publish(key string, oldVal, newVal interface{}, caller string) {
+	evt := WatchEvent{Key: key, OldValue: oldVal, NewValue: newVal, Caller: caller}
+	if subsRaw, ok := m.keySubs.Load(key); ok {
+		subsRaw.(*sync.Map).Range(func(_, v interface{}) bool {
+			sub := v.(*subscription)
+			if m.isAllowed(key, OpRead, sub.caller) {
+				sub.send(evt)
+			}
+			return true
+		})
 	}
-	return res, true
+	m.allSubs.Range(func(_, v interface{}) bool {
+		sub := v.(*subscription)
+		if m.isAllowed(key, OpRead, sub.caller) {
+			sub.send(evt)
+		}
+		return true
+	})
 }
 
 // Returns only initial/descriptor properties.
@@ -209,6 +768,26 @@ func simpleType(val interface{}) string {
 		return "number"
 	}
 	t := reflect.TypeOf(val)
+	if t == nil {
+		return "null"
+	}
+	if t.Kind() == reflect.Ptr {
+		v := reflect.ValueOf(val)
+		if v.IsNil() {
+			return "null"
+		}
+		return simpleType(v.Elem().Interface())
+	}
+	return SimpleTypeOf(t)
+}
+
+// SimpleTypeOf maps a Go reflect.Type to the same descriptor vocabulary
+// simpleType uses for values ("null", "boolean", "string", "number",
+// "function", "array", "object", "promise"). Unlike simpleType, it works
+// from static type information alone, so it's what the generics-based
+// microenv/typed package uses to validate a Property[T]'s T against a
+// property's declared descriptor type without needing a live value.
+func SimpleTypeOf(t reflect.Type) string {
 	if t == nil {
 		return "null"
 	}
@@ -227,13 +806,11 @@ func simpleType(val interface{}) string {
 	case reflect.Map, reflect.Struct:
 		return "object"
 	case reflect.Ptr:
-		v := reflect.ValueOf(val)
-		if v.IsNil() {
-			return "null"
-		}
-		return simpleType(v.Elem().Interface())
+		return SimpleTypeOf(t.Elem())
 	case reflect.Chan:
 		return "promise"
+	case reflect.Interface:
+		return "object"
 	default:
 		return "object"
 	}
@@ -249,9 +826,13 @@ func (m *MicroEnv) Descriptor() map[string]interface{} {
 			"key":  k.(string),
 			"type": simpleType(v),
 		}
-		// Add private descriptor if set
-		if m.privateFlags != nil && m.privateFlags[k.(string)] {
-			child["private"] = true
+		// Add the ACL, if this property has one, as an "access" object.
+		if rule, ok := m.acl[k.(string)]; ok {
+			access := map[string]interface{}{}
+			for op, specs := range *rule {
+				access[string(op)] = specs
+			}
+			child["access"] = access
 		}
 		children = append(children, child)
 		return true
@@ -1,6 +1,8 @@
 package microenv
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -248,6 +250,412 @@ func TestMicroEnvMultiAwaiters(t *testing.T) {
 	}
 }
 
+func TestMicroEnvGetCtxCancelSubset(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"foo": 1})
+	const n = 5
+	cancelled := map[int]bool{1: true, 3: true}
+
+	type waiter struct {
+		idx    int
+		ctx    context.Context
+		cancel context.CancelFunc
+		ch     <-chan interface{}
+	}
+	waiters := make([]waiter, n)
+	for i := 0; i < n; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		_, ch, ok := env.GetCtx(ctx, "foo", true, "")
+		if !ok {
+			t.Fatal("expected GetCtx to succeed")
+		}
+		waiters[i] = waiter{idx: i, ctx: ctx, cancel: cancel, ch: ch}
+	}
+
+	for i, w := range waiters {
+		if cancelled[i] {
+			w.cancel()
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	env.Set("foo", 42, "")
+
+	for i, w := range waiters {
+		select {
+		case val, ok := <-w.ch:
+			if cancelled[i] {
+				if ok {
+					t.Errorf("waiter %d: expected closed channel with no value, got %v", i, val)
+				}
+			} else {
+				if !ok || val != 42 {
+					t.Errorf("waiter %d: expected 42, got %v (ok=%v)", i, val, ok)
+				}
+			}
+		case <-time.After(200 * time.Millisecond):
+			t.Errorf("waiter %d: timed out", i)
+		}
+	}
+
+	if _, ok := env.awaiters.Load("foo"); ok {
+		t.Error("expected awaiters entry to be cleaned up after resolve")
+	}
+}
+
+func TestMicroEnvGetCtxCancelAllRemovesAwaiter(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"foo": 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	_, ch, ok := env.GetCtx(ctx, "foo", true, "")
+	if !ok {
+		t.Fatal("expected GetCtx to succeed")
+	}
+	cancel()
+	select {
+	case val, ok := <-ch:
+		if ok {
+			t.Errorf("expected closed channel with no value, got %v", val)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timed out waiting for cancellation")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := env.awaiters.Load("foo"); ok {
+		t.Error("expected awaiters entry to be removed once its only waiter is cancelled")
+	}
+
+	// A fresh Get on the same key should start a new Awaiter rather than
+	// joining the cancelled one.
+	_, ch2, ok := env.Get("foo", true, "")
+	if !ok {
+		t.Fatal("expected Get to succeed")
+	}
+	env.Set("foo", 7, "")
+	if v := <-ch2; v != 7 {
+		t.Errorf("expected 7, got %v", v)
+	}
+}
+
+func TestMicroEnvWatch(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"x": 1})
+	sub, ok := env.Watch("x", "")
+	if !ok {
+		t.Fatal("expected Watch to succeed")
+	}
+	defer sub.Close()
+
+	env.Set("x", 2, "")
+	env.Set("x", 3, "")
+
+	for _, want := range []interface{}{2, 3} {
+		select {
+		case evt := <-sub.Events():
+			if evt.Key != "x" || evt.NewValue != want {
+				t.Errorf("unexpected event: %+v", evt)
+			}
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("timed out waiting for Set(%v)", want)
+		}
+	}
+}
+
+func TestMicroEnvWatchAll(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"a": 1, "b": 2})
+	sub := env.WatchAll("")
+	defer sub.Close()
+
+	env.Set("a", 10, "")
+	env.Set("b", 20, "")
+
+	seen := map[string]interface{}{}
+	for i := 0; i < 2; i++ {
+		select {
+		case evt := <-sub.Events():
+			seen[evt.Key] = evt.NewValue
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+	if seen["a"] != 10 || seen["b"] != 20 {
+		t.Errorf("unexpected events: %+v", seen)
+	}
+}
+
+func TestMicroEnvWatchManySubscribersAndClose(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"x": 0})
+	const n = 8
+	subs := make([]Subscription, n)
+	for i := range subs {
+		sub, ok := env.Watch("x", "")
+		if !ok {
+			t.Fatal("expected Watch to succeed")
+		}
+		subs[i] = sub
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i += 2 {
+		wg.Add(1)
+		go func(sub Subscription) {
+			defer wg.Done()
+			sub.Close()
+		}(subs[i])
+	}
+	wg.Wait()
+
+	env.Set("x", 99, "")
+	for i, sub := range subs {
+		if i%2 == 0 {
+			continue
+		}
+		select {
+		case evt := <-sub.Events():
+			if evt.NewValue != 99 {
+				t.Errorf("subscriber %d: unexpected value %v", i, evt.NewValue)
+			}
+		case <-time.After(200 * time.Millisecond):
+			t.Errorf("subscriber %d: timed out", i)
+		}
+		sub.Close()
+	}
+}
+
+func TestMicroEnvWatchSlowConsumerDropsOldest(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"x": 0}, WithWatchBuffer(2))
+	sub, ok := env.Watch("x", "")
+	if !ok {
+		t.Fatal("expected Watch to succeed")
+	}
+	defer sub.Close()
+
+	// Never drain: Set must not block even though the buffer overflows.
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 5; i++ {
+			env.Set("x", i, "")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Set blocked on a slow watch consumer")
+	}
+
+	if d := sub.Dropped(); d == 0 {
+		t.Error("expected some events to be dropped for a slow consumer")
+	}
+
+	// The last value in the (size-2) buffer should still be the most recent.
+	var last WatchEvent
+	for {
+		select {
+		case evt := <-sub.Events():
+			last = evt
+			continue
+		default:
+		}
+		break
+	}
+	if last.NewValue != 5 {
+		t.Errorf("expected newest event to survive the drop, got %v", last.NewValue)
+	}
+}
+
+func TestMicroEnvAccessReadRestrictedToRole(t *testing.T) {
+	cdesc := map[string]interface{}{
+		"children": []map[string]interface{}{
+			{"key": "secret", "type": "number", "access": map[string]interface{}{
+				"read": []interface{}{"role:admin"},
+			}},
+		},
+	}
+	env := NewMicroEnv(
+		map[string]interface{}{"secret": 42},
+		WithCustomDescriptor(cdesc),
+		WithCallerRoles(func(caller string) []string {
+			if caller == "alice" {
+				return []string{"admin"}
+			}
+			return nil
+		}),
+	)
+
+	if _, _, ok := env.Get("secret", false, "bob"); ok {
+		t.Error("bob should not be able to read 'secret'")
+	}
+	if _, _, ok := env.Get("secret", false, "alice"); !ok {
+		t.Error("alice (role:admin) should be able to read 'secret'")
+	}
+	// Write isn't restricted in this ACL entry, so it stays public.
+	env.Set("secret", 7, "bob")
+	if val, _, ok := env.Get("secret", false, "alice"); !ok || val != 7 {
+		t.Errorf("expected bob's write to succeed, got %v, %v", val, ok)
+	}
+}
+
+func TestMicroEnvAccessWriteRestricted(t *testing.T) {
+	cdesc := map[string]interface{}{
+		"children": []map[string]interface{}{
+			{"key": "x", "type": "number", "access": map[string]interface{}{
+				"write": []interface{}{"owner"},
+			}},
+		},
+	}
+	env := NewMicroEnv(map[string]interface{}{"x": 1}, WithCustomDescriptor(cdesc))
+
+	env.Set("x", 2, "someone-else")
+	if val, _, _ := env.Get("x", false, ""); val != 1 {
+		t.Errorf("non-owner write should have been rejected, got %v", val)
+	}
+	env.Set("x", 2, "")
+	if val, _, _ := env.Get("x", false, ""); val != 2 {
+		t.Errorf("owner write should have succeeded, got %v", val)
+	}
+}
+
+func TestMicroEnvAccessCallReadableNotCallable(t *testing.T) {
+	cdesc := map[string]interface{}{
+		"children": []map[string]interface{}{
+			{"key": "fn", "type": "function", "access": map[string]interface{}{
+				"call": []interface{}{"owner"},
+			}},
+		},
+	}
+	fn := func(payload interface{}, data *sync.Map, caller interface{}) int { return 1 }
+	env := NewMicroEnv(map[string]interface{}{"fn": fn}, WithCustomDescriptor(cdesc))
+
+	if _, _, ok := env.Get("fn", false, "guest"); !ok {
+		t.Error("'fn' should still be readable by a non-owner caller")
+	}
+	if _, ok := env.Call("fn", nil, "guest"); ok {
+		t.Error("'fn' should not be callable by a non-owner caller")
+	}
+	if _, ok := env.Call("fn", nil, ""); !ok {
+		t.Error("'fn' should be callable by the owner")
+	}
+}
+
+func TestMicroEnvAccessLegacyPrivateFlag(t *testing.T) {
+	cdesc := map[string]interface{}{
+		"children": []map[string]interface{}{
+			{"key": "x", "type": "number", "private": true},
+		},
+	}
+	env := NewMicroEnv(map[string]interface{}{"x": 1}, WithCustomDescriptor(cdesc))
+
+	if _, _, ok := env.Get("x", false, "someone"); ok {
+		t.Error("legacy private:true should block non-owner reads")
+	}
+	if _, _, ok := env.Get("x", false, ""); !ok {
+		t.Error("legacy private:true should still allow owner reads")
+	}
+	if _, ok := env.Call("x", nil, "someone"); ok {
+		t.Error("legacy private:true should block non-owner calls too")
+	}
+}
+
+func TestMicroEnvCallShapes(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{
+		"payloadOnly": func(payload int) int { return payload * 2 },
+		"withData": func(payload int, data *sync.Map) int {
+			data.Store("seen", payload)
+			return payload + 1
+		},
+		"withCaller": func(payload int, data *sync.Map, caller string) string {
+			return caller
+		},
+		"withCtx": func(ctx context.Context, payload int) int { return payload + 100 },
+	})
+
+	if res, ok := env.Call("payloadOnly", 3, ""); !ok || res[0] != 6 {
+		t.Fatalf("payloadOnly: %v, %v", res, ok)
+	}
+	if res, ok := env.Call("withData", 3, ""); !ok || res[0] != 4 {
+		t.Fatalf("withData: %v, %v", res, ok)
+	}
+	if res, ok := env.Call("withCaller", 0, "alice"); !ok || res[0] != "alice" {
+		t.Fatalf("withCaller: %v, %v", res, ok)
+	}
+	if res, ok := env.Call("withCtx", 3, ""); !ok || res[0] != 103 {
+		t.Fatalf("withCtx: %v, %v", res, ok)
+	}
+}
+
+func TestMicroEnvCallVariadicPayload(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{
+		"sum": func(nums ...int) int {
+			total := 0
+			for _, n := range nums {
+				total += n
+			}
+			return total
+		},
+	})
+	res, ok := env.Call("sum", []int{1, 2, 3, 4}, "")
+	if !ok || res[0] != 10 {
+		t.Fatalf("variadic Call: %v, %v", res, ok)
+	}
+}
+
+func TestMicroEnvCallPayloadMismatch(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{
+		"sum": func(nums []int) int {
+			total := 0
+			for _, n := range nums {
+				total += n
+			}
+			return total
+		},
+	})
+	if res, ok := env.Call("sum", "not-a-slice", ""); ok {
+		t.Fatalf("Call with mismatched payload type = %v, %v, want ok=false", res, ok)
+	}
+}
+
+func TestMicroEnvCallErr(t *testing.T) {
+	boom := errors.New("boom")
+	env := NewMicroEnv(map[string]interface{}{
+		"ok": func(payload int) (int, error) { return payload, nil },
+		"fails": func(payload int) (int, error) {
+			return 0, boom
+		},
+	})
+
+	res, err, ok := env.CallErr("ok", 5, "")
+	if !ok || err != nil || len(res) != 1 || res[0] != 5 {
+		t.Fatalf("CallErr(ok): %v, %v, %v", res, err, ok)
+	}
+	res, err, ok = env.CallErr("fails", 0, "")
+	if !ok || err != boom || len(res) != 1 {
+		t.Fatalf("CallErr(fails): %v, %v, %v", res, err, ok)
+	}
+	// Call keeps folding the error into the result slice, unchanged.
+	plain, ok := env.Call("fails", 0, "")
+	if !ok || len(plain) != 2 {
+		t.Fatalf("Call(fails): %v, %v", plain, ok)
+	}
+}
+
+func TestMicroEnvRegisterFunc(t *testing.T) {
+	env := NewMicroEnv(map[string]interface{}{"fn": 0})
+	if err := env.RegisterFunc("fn", func(payload int) int { return payload * 3 }); err != nil {
+		t.Fatalf("RegisterFunc: %v", err)
+	}
+	if res, ok := env.Call("fn", 4, ""); !ok || res[0] != 12 {
+		t.Fatalf("Call after RegisterFunc: %v, %v", res, ok)
+	}
+
+	if err := env.RegisterFunc("missing", func() int { return 0 }); err == nil {
+		t.Error("expected RegisterFunc to reject an undeclared key")
+	}
+	if err := env.RegisterFunc("fn", 123); err == nil {
+		t.Error("expected RegisterFunc to reject a non-function")
+	}
+	if err := env.RegisterFunc("fn", func(a, b, c, d int) int { return 0 }); err == nil {
+		t.Error("expected RegisterFunc to reject an unsupported arity")
+	}
+}
+
 func TestAwaiterGetAfterResolved(t *testing.T) {
 	env := NewMicroEnv(map[string]interface{}{"foo": 10})
 	env.Set("foo", 5, "")
@@ -0,0 +1,282 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ceil-go/microenv"
+)
+
+// Client is the remote-process counterpart to Server: it mirrors
+// MicroEnv's Get/Set/Call/Watch API over a single net.Conn.
+type Client struct {
+	conn net.Conn
+
+	nextID uint64
+
+	mu      sync.Mutex
+	pending map[string]chan Message
+
+	subMu sync.Mutex
+	subs  map[string]chan Message
+}
+
+// Dial wraps an already-connected conn (e.g. from net.Dial) as a Client.
+// Authentication, if any, is the Server's Authenticator's job based on the
+// connection itself (e.g. TLS client certs) - Client has no caller field
+// to set because a client-supplied caller is never trusted.
+func Dial(conn net.Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		pending: make(map[string]chan Message),
+		subs:    make(map[string]chan Message),
+	}
+	go c.readLoop()
+	return c
+}
+
+func (c *Client) readLoop() {
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		// The initial ack for "next"/"watch" carries both ID and Sub, and
+		// must go to the pending request (it's what hands the caller
+		// resp.Sub to register with). Only messages with no matching
+		// pending request - i.e. later notifications on an established
+		// subscription - are routed by Sub instead.
+		c.mu.Lock()
+		ch, ok := c.pending[msg.ID]
+		if ok {
+			delete(c.pending, msg.ID)
+		}
+		c.mu.Unlock()
+		if ok {
+			ch <- msg
+			continue
+		}
+		if msg.Sub != "" {
+			c.subMu.Lock()
+			sch, sok := c.subs[msg.Sub]
+			if sok && msg.Done {
+				delete(c.subs, msg.Sub)
+			}
+			c.subMu.Unlock()
+			if sok {
+				sch <- msg
+				if msg.Done {
+					close(sch)
+				}
+			}
+		}
+	}
+	c.failPending()
+}
+
+// failPending unblocks any caller still waiting on a response when the
+// connection drops out from under it.
+func (c *Client) failPending() {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]chan Message)
+	c.mu.Unlock()
+	for _, ch := range pending {
+		ch <- Message{OK: false, Error: "rpc: connection closed"}
+	}
+}
+
+func (c *Client) request(req Message) (Message, error) {
+	req.ID = fmt.Sprintf("%d", atomic.AddUint64(&c.nextID, 1))
+	ch := make(chan Message, 1)
+	c.mu.Lock()
+	c.pending[req.ID] = ch
+	c.mu.Unlock()
+	b, err := json.Marshal(req)
+	if err != nil {
+		return Message{}, err
+	}
+	if _, err := c.conn.Write(append(b, '\n')); err != nil {
+		return Message{}, err
+	}
+	resp := <-ch
+	if !resp.OK {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// Descriptor fetches the remote MicroEnv's Descriptor().
+func (c *Client) Descriptor() (map[string]interface{}, error) {
+	resp, err := c.request(Message{Method: "descriptor"})
+	if err != nil {
+		return nil, err
+	}
+	desc, _ := resp.Result.(map[string]interface{})
+	return desc, nil
+}
+
+// Get mirrors MicroEnv.Get(key, false, caller).
+func (c *Client) Get(key string) (interface{}, bool) {
+	resp, err := c.request(Message{Method: "get", Key: key})
+	if err != nil {
+		return nil, false
+	}
+	return resp.Result, true
+}
+
+// Set mirrors MicroEnv.Set(key, val, caller).
+func (c *Client) Set(key string, val interface{}) {
+	c.request(Message{Method: "set", Key: key, Value: val})
+}
+
+// Call mirrors MicroEnv.Call(key, payload, caller).
+func (c *Client) Call(key string, payload interface{}) ([]interface{}, bool) {
+	resp, err := c.request(Message{Method: "call", Key: key, Payload: payload})
+	if err != nil {
+		return nil, false
+	}
+	results, _ := resp.Result.([]interface{})
+	return results, true
+}
+
+// Next mirrors GetCtx(ctx, key, true, caller): it blocks until the next
+// Set on key, or ctx is cancelled.
+func (c *Client) Next(ctx context.Context, key string) (interface{}, error) {
+	resp, err := c.request(Message{Method: "next", Key: key})
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan Message, 1)
+	c.subMu.Lock()
+	c.subs[resp.Sub] = ch
+	c.subMu.Unlock()
+	select {
+	case msg := <-ch:
+		if !msg.OK {
+			return nil, errors.New(msg.Error)
+		}
+		return msg.Result, nil
+	case <-ctx.Done():
+		c.request(Message{Method: "unwatch", Sub: resp.Sub})
+		return nil, ctx.Err()
+	}
+}
+
+// clientWatchBuffer bounds a ClientSubscription's events channel, mirroring
+// microenv's own defaultWatchBuffer for the in-process case.
+const clientWatchBuffer = 16
+
+// ClientSubscription is the Client-side handle returned by Watch.
+type ClientSubscription struct {
+	id     string
+	client *Client
+	events chan microenv.WatchEvent
+
+	mu      sync.Mutex
+	closed  bool
+	dropped uint64
+}
+
+func (s *ClientSubscription) Events() <-chan microenv.WatchEvent { return s.events }
+
+// Dropped reports how many events were discarded because the consumer
+// wasn't keeping up, mirroring microenv.Subscription.Dropped.
+func (s *ClientSubscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+func (s *ClientSubscription) Close() {
+	s.client.request(Message{Method: "unwatch", Sub: s.id})
+}
+
+// send delivers evt without ever blocking: if events is full, the oldest
+// queued event is dropped to make room. This runs on the forwarding
+// goroutine started by Watch, which in turn is what keeps readLoop - the
+// single goroutine demultiplexing the whole connection - from ever
+// blocking on a slow subscription consumer.
+func (s *ClientSubscription) send(evt microenv.WatchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for {
+		select {
+		case s.events <- evt:
+			return
+		default:
+		}
+		select {
+		case <-s.events:
+			s.dropped++
+		default:
+			return
+		}
+	}
+}
+
+// Watch mirrors MicroEnv.Watch(key, caller).
+func (c *Client) Watch(key string) (*ClientSubscription, error) {
+	resp, err := c.request(Message{Method: "watch", Key: key})
+	if err != nil {
+		return nil, err
+	}
+	raw := make(chan Message, 16)
+	c.subMu.Lock()
+	c.subs[resp.Sub] = raw
+	c.subMu.Unlock()
+
+	sub := &ClientSubscription{
+		id:     resp.Sub,
+		client: c,
+		events: make(chan microenv.WatchEvent, clientWatchBuffer),
+	}
+	go func() {
+		for msg := range raw {
+			if msg.Event != nil {
+				sub.send(*msg.Event)
+			}
+		}
+		sub.mu.Lock()
+		sub.closed = true
+		sub.mu.Unlock()
+		close(sub.events)
+	}()
+	return sub, nil
+}
+
+// Face builds a MicroEnv-style face map from the remote Descriptor, so a
+// remote process can consume it transparently via the same
+// *microenv.FacePropertyAPI shape NewMicroEnv produces in-process.
+func (c *Client) Face() (map[string]*microenv.FacePropertyAPI, error) {
+	desc, err := c.Descriptor()
+	if err != nil {
+		return nil, err
+	}
+	children, _ := desc["children"].([]interface{})
+	face := make(map[string]*microenv.FacePropertyAPI, len(children))
+	for _, childRaw := range children {
+		entry, ok := childRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _ := entry["key"].(string)
+		k := key
+		face[k] = &microenv.FacePropertyAPI{
+			Get: func(caller string) (interface{}, bool) { return c.Get(k) },
+			Set: func(val interface{}, caller string) { c.Set(k, val) },
+		}
+	}
+	return face, nil
+}
@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ceil-go/microenv"
+)
+
+// HTTPAuthenticator resolves the caller identity for an incoming HTTP
+// request (e.g. from a header or cookie). Like Authenticator, it's the
+// only trusted source of the caller - request bodies are never trusted
+// to self-report one.
+type HTTPAuthenticator func(r *http.Request) (caller string, err error)
+
+// HTTPHandler serves a *microenv.MicroEnv over HTTP: descriptor/get/set/
+// call are plain request/response, watch is served as Server-Sent Events,
+// and next is a long-poll that answers with a single JSON body, so neither
+// streaming method needs a WebSocket upgrade.
+type HTTPHandler struct {
+	Env  *microenv.MicroEnv
+	Auth HTTPAuthenticator
+}
+
+func NewHTTPHandler(env *microenv.MicroEnv, auth HTTPAuthenticator) *HTTPHandler {
+	return &HTTPHandler{Env: env, Auth: auth}
+}
+
+func (h *HTTPHandler) caller(r *http.Request) (string, error) {
+	if h.Auth == nil {
+		return "", nil
+	}
+	return h.Auth(r)
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	caller, err := h.caller(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/descriptor":
+		writeJSON(w, h.Env.Descriptor())
+	case "/get":
+		h.serveGet(w, r, caller)
+	case "/set":
+		h.serveSet(w, r, caller)
+	case "/call":
+		h.serveCall(w, r, caller)
+	case "/watch":
+		h.serveWatch(w, r, caller)
+	case "/next":
+		h.serveNext(w, r, caller)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (h *HTTPHandler) serveGet(w http.ResponseWriter, r *http.Request, caller string) {
+	key := r.URL.Query().Get("key")
+	val, _, ok := h.Env.Get(key, false, caller)
+	if !ok {
+		http.Error(w, "not allowed or no such key", http.StatusForbidden)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"value": encodeValue(key, val)})
+}
+
+func (h *HTTPHandler) serveSet(w http.ResponseWriter, r *http.Request, caller string) {
+	key := r.URL.Query().Get("key")
+	var body struct {
+		Value interface{} `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.Env.Set(key, body.Value, caller)
+	writeJSON(w, map[string]interface{}{"ok": true})
+}
+
+func (h *HTTPHandler) serveCall(w http.ResponseWriter, r *http.Request, caller string) {
+	key := r.URL.Query().Get("key")
+	var body struct {
+		Payload interface{} `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	results, ok := h.Env.Call(key, body.Payload, caller)
+	if !ok {
+		http.Error(w, "call refused or no such function", http.StatusForbidden)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"result": results})
+}
+
+// serveWatch streams WatchEvents for ?key= as Server-Sent Events until the
+// client disconnects (r.Context().Done()).
+func (h *HTTPHandler) serveWatch(w http.ResponseWriter, r *http.Request, caller string) {
+	key := r.URL.Query().Get("key")
+	sub, ok := h.Env.Watch(key, caller)
+	if !ok {
+		http.Error(w, "not allowed or no such key", http.StatusForbidden)
+		return
+	}
+	defer sub.Close()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case evt, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			b, err := json.Marshal(evt)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", b)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// serveNext long-polls for the next Set on ?key=, returning as soon as it
+// resolves or the client's request context is cancelled.
+func (h *HTTPHandler) serveNext(w http.ResponseWriter, r *http.Request, caller string) {
+	key := r.URL.Query().Get("key")
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	_, ch, ok := h.Env.GetCtx(ctx, key, true, caller)
+	if !ok {
+		http.Error(w, "not allowed or no such key", http.StatusForbidden)
+		return
+	}
+	select {
+	case val, ok := <-ch:
+		if !ok {
+			http.Error(w, "cancelled", http.StatusGatewayTimeout)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"value": encodeValue(key, val)})
+	case <-r.Context().Done():
+	}
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ceil-go/microenv"
+)
+
+func TestHTTPGetSetCall(t *testing.T) {
+	sum := func(payload interface{}, data *sync.Map, caller string) int {
+		vals, _ := payload.([]interface{})
+		total := 0
+		for _, v := range vals {
+			n, _ := v.(float64)
+			total += int(n)
+		}
+		return total
+	}
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0, "sum": sum})
+	srv := httptest.NewServer(NewHTTPHandler(env, nil))
+	defer srv.Close()
+
+	var getResp struct {
+		Value float64 `json:"value"`
+	}
+	httpGetJSON(t, srv.URL+"/get?key=x", &getResp)
+	if getResp.Value != 1.0 {
+		t.Fatalf("get x = %v, want 1", getResp.Value)
+	}
+
+	setBody, _ := json.Marshal(map[string]interface{}{"value": 2.0})
+	resp, err := http.Post(srv.URL+"/set?key=x", "application/json", bytes.NewReader(setBody))
+	if err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	resp.Body.Close()
+
+	httpGetJSON(t, srv.URL+"/get?key=x", &getResp)
+	if getResp.Value != 2.0 {
+		t.Fatalf("get x after set = %v, want 2", getResp.Value)
+	}
+
+	callBody, _ := json.Marshal(map[string]interface{}{"payload": []interface{}{2.0, 3.0}})
+	resp, err = http.Post(srv.URL+"/call?key=sum", "application/json", bytes.NewReader(callBody))
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	defer resp.Body.Close()
+	var callResp struct {
+		Result []interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&callResp); err != nil {
+		t.Fatalf("decode call response: %v", err)
+	}
+	if len(callResp.Result) != 1 || callResp.Result[0] != 5.0 {
+		t.Fatalf("call result = %v, want [5]", callResp.Result)
+	}
+}
+
+func httpGetJSON(t *testing.T, url string, v interface{}) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("decode %s: %v", url, err)
+	}
+}
+
+func TestHTTPWatchSSE(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	srv := httptest.NewServer(NewHTTPHandler(env, nil))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/watch?key=x")
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	time.Sleep(30 * time.Millisecond)
+	env.Set("x", 2.0, "")
+
+	var evt microenv.WatchEvent
+	if err := readSSEEvent(reader, &evt); err != nil {
+		t.Fatalf("reading SSE event: %v", err)
+	}
+	if evt.NewValue != 2.0 {
+		t.Errorf("watch event NewValue = %v, want 2", evt.NewValue)
+	}
+}
+
+func TestHTTPNextLongPoll(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	srv := httptest.NewServer(NewHTTPHandler(env, nil))
+	defer srv.Close()
+
+	done := make(chan struct{})
+	var getResp struct {
+		Value float64 `json:"value"`
+	}
+	go func() {
+		defer close(done)
+		httpGetJSON(t, srv.URL+"/next?key=x", &getResp)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	env.Set("x", 9.0, "")
+
+	select {
+	case <-done:
+		if getResp.Value != 9.0 {
+			t.Errorf("next result = %v, want 9", getResp.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for /next")
+	}
+}
+
+// readSSEEvent reads a single "data: ...\n\n" frame and unmarshals its
+// payload into v.
+func readSSEEvent(r *bufio.Reader, v interface{}) error {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		return json.Unmarshal([]byte(data), v)
+	}
+}
@@ -0,0 +1,290 @@
+// Package rpc serves a *microenv.MicroEnv over a line-delimited JSON
+// protocol on a net.Conn, and provides a Client that mirrors the
+// in-process MicroEnv API for a remote process to consume.
+//
+// Every line on the wire is a single JSON-encoded Message: requests carry
+// an "id" and a "method" (descriptor, get, set, call, watch, unwatch,
+// next); responses echo the "id". watch and next additionally get an
+// unsolicited stream of Messages carrying the same "sub" id they were
+// acknowledged with, terminated by a Message with "done": true.
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ceil-go/microenv"
+)
+
+// Authenticator resolves the caller identity for a newly accepted
+// connection. The caller string clients put in their requests is never
+// trusted directly; Auth is the only source of truth for who a
+// connection's requests are made as.
+type Authenticator func(conn net.Conn) (caller string, err error)
+
+// Message is the single wire type for both directions: requests, their
+// responses, and the unsolicited notifications watch/next produce.
+type Message struct {
+	ID      string      `json:"id,omitempty"`
+	Method  string      `json:"method,omitempty"`
+	Key     string      `json:"key,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+	Payload interface{} `json:"payload,omitempty"`
+
+	OK     bool        `json:"ok,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+
+	// Sub identifies a watch/next subscription: returned on the initial
+	// response, then echoed on every subsequent notification for it.
+	Sub string `json:"sub,omitempty"`
+	// Event carries a watch notification's payload.
+	Event *microenv.WatchEvent `json:"event,omitempty"`
+	// Done marks the last Message for a subscription (next resolved,
+	// cancelled, or unwatch'd).
+	Done bool `json:"done,omitempty"`
+}
+
+// encodeValue converts a value destined for the wire into one that round-trips
+// through JSON the way simpleType's vocabulary says it should: functions
+// aren't JSON-serializable, so they cross as an opaque handle the client
+// must invoke through the "call" method instead of reading directly.
+func encodeValue(key string, val interface{}) interface{} {
+	if microenv.SimpleTypeOf(reflect.TypeOf(val)) == "function" {
+		return map[string]interface{}{"handle": key}
+	}
+	return val
+}
+
+// Server serves a *microenv.MicroEnv to one or more connections.
+type Server struct {
+	Env  *microenv.MicroEnv
+	Auth Authenticator
+}
+
+func NewServer(env *microenv.MicroEnv, auth Authenticator) *Server {
+	return &Server{Env: env, Auth: auth}
+}
+
+// Serve accepts connections from ln until it errors (e.g. on Close), and
+// handles each in its own goroutine via ServeConn.
+func (s *Server) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn authenticates conn, then services requests from it until it
+// errors or is closed. Requests are dispatched concurrently so a slow
+// call/next/watch doesn't stall unrelated requests pipelined behind it;
+// responses and notifications are tagged with id/sub so the client can
+// demultiplex them.
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	caller := ""
+	if s.Auth != nil {
+		c, err := s.Auth(conn)
+		if err != nil {
+			return
+		}
+		caller = c
+	}
+
+	sess := newSession(s.Env, conn, caller)
+	defer sess.closeAll()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var wg sync.WaitGroup
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		if len(line) == 0 {
+			continue
+		}
+		var req Message
+		if err := json.Unmarshal(line, &req); err != nil {
+			sess.write(Message{OK: false, Error: "invalid json: " + err.Error()})
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sess.handle(req)
+		}()
+	}
+	wg.Wait()
+}
+
+// session holds per-connection state: the authenticated caller, a mutex
+// serializing writes (net.Conn.Write isn't safe for concurrent callers),
+// and the live watch/next subscriptions so unwatch and connection close
+// can tear them down.
+type session struct {
+	env    *microenv.MicroEnv
+	conn   net.Conn
+	caller string
+
+	writeMu sync.Mutex
+
+	nextSub uint64
+	subMu   sync.Mutex
+	subs    map[string]func()
+}
+
+func newSession(env *microenv.MicroEnv, conn net.Conn, caller string) *session {
+	return &session{env: env, conn: conn, caller: caller, subs: make(map[string]func())}
+}
+
+func (sess *session) write(msg Message) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	sess.writeMu.Lock()
+	defer sess.writeMu.Unlock()
+	sess.conn.Write(b)
+}
+
+func (sess *session) newSubID() string {
+	return fmt.Sprintf("%d", atomic.AddUint64(&sess.nextSub, 1))
+}
+
+func (sess *session) registerSub(id string, cancel func()) {
+	sess.subMu.Lock()
+	sess.subs[id] = cancel
+	sess.subMu.Unlock()
+}
+
+func (sess *session) takeSub(id string) (func(), bool) {
+	sess.subMu.Lock()
+	defer sess.subMu.Unlock()
+	cancel, ok := sess.subs[id]
+	if ok {
+		delete(sess.subs, id)
+	}
+	return cancel, ok
+}
+
+func (sess *session) closeAll() {
+	sess.subMu.Lock()
+	subs := sess.subs
+	sess.subs = make(map[string]func())
+	sess.subMu.Unlock()
+	for _, cancel := range subs {
+		cancel()
+	}
+}
+
+// handle dispatches req and writes its response. It recovers from any
+// panic along the way (e.g. a "call" whose payload doesn't match the
+// registered function's parameter types closely enough for microenv's own
+// checks to catch) and reports it as an ordinary error response instead of
+// taking down the connection's other in-flight requests with it.
+func (sess *session) handle(req Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			sess.write(Message{ID: req.ID, OK: false, Error: fmt.Sprintf("internal error: %v", r)})
+		}
+	}()
+	switch req.Method {
+	case "descriptor":
+		sess.write(Message{ID: req.ID, OK: true, Result: sess.env.Descriptor()})
+
+	case "get":
+		val, _, ok := sess.env.Get(req.Key, false, sess.caller)
+		if !ok {
+			sess.write(Message{ID: req.ID, OK: false, Error: "not allowed or no such key"})
+			return
+		}
+		sess.write(Message{ID: req.ID, OK: true, Result: encodeValue(req.Key, val)})
+
+	case "set":
+		sess.env.Set(req.Key, req.Value, sess.caller)
+		sess.write(Message{ID: req.ID, OK: true})
+
+	case "call":
+		// Use CallErr rather than Call so a trailing error return is split
+		// out instead of riding along in results: most concrete error
+		// types have no exported fields, so json.Marshal would silently
+		// flatten one to {} and lose its message crossing the wire.
+		results, callErr, ok := sess.env.CallErr(req.Key, req.Payload, sess.caller)
+		if !ok {
+			sess.write(Message{ID: req.ID, OK: false, Error: "call refused or no such function"})
+			return
+		}
+		if callErr != nil {
+			results = append(results, callErr.Error())
+		}
+		sess.write(Message{ID: req.ID, OK: true, Result: results})
+
+	case "next":
+		sess.handleNext(req)
+
+	case "watch":
+		sess.handleWatch(req)
+
+	case "unwatch":
+		if cancel, ok := sess.takeSub(req.Sub); ok {
+			cancel()
+		}
+		sess.write(Message{ID: req.ID, OK: true})
+
+	default:
+		sess.write(Message{ID: req.ID, OK: false, Error: "unknown method: " + req.Method})
+	}
+}
+
+func (sess *session) handleNext(req Message) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, ch, ok := sess.env.GetCtx(ctx, req.Key, true, sess.caller)
+	if !ok {
+		cancel()
+		sess.write(Message{ID: req.ID, OK: false, Error: "not allowed or no such key"})
+		return
+	}
+	subID := sess.newSubID()
+	sess.registerSub(subID, cancel)
+	sess.write(Message{ID: req.ID, OK: true, Sub: subID})
+	go func() {
+		defer func() {
+			sess.takeSub(subID)
+			cancel()
+		}()
+		val, ok := <-ch
+		if !ok {
+			sess.write(Message{Sub: subID, OK: false, Error: "cancelled", Done: true})
+			return
+		}
+		sess.write(Message{Sub: subID, OK: true, Result: encodeValue(req.Key, val), Done: true})
+	}()
+}
+
+func (sess *session) handleWatch(req Message) {
+	sub, ok := sess.env.Watch(req.Key, sess.caller)
+	if !ok {
+		sess.write(Message{ID: req.ID, OK: false, Error: "not allowed or no such key"})
+		return
+	}
+	subID := sess.newSubID()
+	sess.registerSub(subID, sub.Close)
+	sess.write(Message{ID: req.ID, OK: true, Sub: subID})
+	go func() {
+		for evt := range sub.Events() {
+			evt := evt
+			sess.write(Message{Sub: subID, OK: true, Event: &evt})
+		}
+		sess.takeSub(subID)
+		sess.write(Message{Sub: subID, OK: true, Done: true})
+	}()
+}
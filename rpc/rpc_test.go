@@ -0,0 +1,229 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ceil-go/microenv"
+)
+
+func newTestServerClient(t *testing.T, env *microenv.MicroEnv, auth Authenticator) *Client {
+	t.Helper()
+	serverConn, clientConn := net.Pipe()
+	s := NewServer(env, auth)
+	go s.ServeConn(serverConn)
+	t.Cleanup(func() { clientConn.Close() })
+	return Dial(clientConn)
+}
+
+func TestRPCDescriptorGetSet(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	c := newTestServerClient(t, env, nil)
+
+	desc, err := c.Descriptor()
+	if err != nil {
+		t.Fatalf("Descriptor: %v", err)
+	}
+	if _, ok := desc["children"]; !ok {
+		t.Fatalf("expected a children field in %+v", desc)
+	}
+
+	val, ok := c.Get("x")
+	if !ok || val != 1.0 {
+		t.Fatalf("Get() = %v, %v", val, ok)
+	}
+
+	c.Set("x", 2.0)
+	val, ok = c.Get("x")
+	if !ok || val != 2.0 {
+		t.Fatalf("Get() after Set = %v, %v", val, ok)
+	}
+}
+
+func TestRPCAuthenticatedCaller(t *testing.T) {
+	cdesc := map[string]interface{}{
+		"children": []map[string]interface{}{
+			{"key": "secret", "type": "number", "access": map[string]interface{}{
+				"read": []interface{}{"admin"},
+			}},
+		},
+	}
+	env := microenv.NewMicroEnv(
+		map[string]interface{}{"secret": 42.0},
+		microenv.WithCustomDescriptor(cdesc),
+	)
+	c := newTestServerClient(t, env, func(conn net.Conn) (string, error) {
+		return "admin", nil
+	})
+
+	val, ok := c.Get("secret")
+	if !ok || val != 42.0 {
+		t.Fatalf("expected the authenticated 'admin' caller to read secret, got %v, %v", val, ok)
+	}
+}
+
+func TestRPCCall(t *testing.T) {
+	sum := func(payload interface{}, data *sync.Map, caller string) int {
+		vals, _ := payload.([]interface{})
+		total := 0
+		for _, v := range vals {
+			n, _ := v.(float64)
+			total += int(n)
+		}
+		return total
+	}
+	env := microenv.NewMicroEnv(map[string]interface{}{"sum": sum})
+	c := newTestServerClient(t, env, nil)
+
+	results, ok := c.Call("sum", []interface{}{2.0, 3.0})
+	if !ok || len(results) != 1 {
+		t.Fatalf("Call() = %v, %v", results, ok)
+	}
+	if got, _ := results[0].(float64); got != 5 {
+		t.Errorf("Call() result = %v, want 5", results[0])
+	}
+}
+
+func TestRPCCallPayloadMismatchSurvives(t *testing.T) {
+	sum := func(nums []int) int {
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total
+	}
+	env := microenv.NewMicroEnv(map[string]interface{}{"sum": sum, "x": 1.0})
+	c := newTestServerClient(t, env, nil)
+
+	if _, ok := c.Call("sum", "not-a-slice"); ok {
+		t.Fatalf("Call with mismatched payload type succeeded, want refused")
+	}
+	// The connection, and the server behind it, must survive that.
+	val, ok := c.Get("x")
+	if !ok || val != 1.0 {
+		t.Fatalf("Get after mismatched Call = %v, %v", val, ok)
+	}
+}
+
+func TestRPCCallErrorMessage(t *testing.T) {
+	fails := func(payload interface{}) (int, error) { return 0, errors.New("something went wrong") }
+	env := microenv.NewMicroEnv(map[string]interface{}{"fails": fails})
+	c := newTestServerClient(t, env, nil)
+
+	results, ok := c.Call("fails", 0.0)
+	if !ok || len(results) != 2 {
+		t.Fatalf("Call(fails) = %v, %v", results, ok)
+	}
+	msg, _ := results[1].(string)
+	if msg != "something went wrong" {
+		t.Fatalf("Call(fails) error result = %v, want the error's message string", results[1])
+	}
+}
+
+func TestRPCNext(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	c := newTestServerClient(t, env, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	done := make(chan interface{}, 1)
+	go func() {
+		val, err := c.Next(ctx, "x")
+		if err != nil {
+			t.Errorf("Next: %v", err)
+			return
+		}
+		done <- val
+	}()
+	time.Sleep(30 * time.Millisecond)
+	env.Set("x", 9.0, "")
+	select {
+	case val := <-done:
+		if val != 9.0 {
+			t.Errorf("Next() = %v, want 9", val)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Next")
+	}
+}
+
+func TestRPCWatch(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	c := newTestServerClient(t, env, nil)
+
+	sub, err := c.Watch("x")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer sub.Close()
+
+	env.Set("x", 2.0, "")
+	env.Set("x", 3.0, "")
+
+	for _, want := range []float64{2, 3} {
+		select {
+		case evt := <-sub.Events():
+			if evt.NewValue != want {
+				t.Errorf("got %v, want %v", evt.NewValue, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %v", want)
+		}
+	}
+}
+
+// TestRPCWatchBackpressureDoesNotWedgeConnection guards against a slow/
+// absent Watch consumer backing up readLoop, the single goroutine
+// demultiplexing the whole connection, and starving unrelated requests.
+func TestRPCWatchBackpressureDoesNotWedgeConnection(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	c := newTestServerClient(t, env, nil)
+
+	sub, err := c.Watch("x")
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer sub.Close()
+
+	// Never drain sub.Events() and fire far more Sets than any buffer
+	// could hold.
+	for i := 0; i < 100; i++ {
+		env.Set("x", float64(i), "")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if val, ok := c.Get("x"); !ok || val != 99.0 {
+			t.Errorf("Get() = %v, %v, want 99, true", val, ok)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Get() blocked behind an undrained Watch subscription")
+	}
+}
+
+func TestRPCFace(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1.0})
+	c := newTestServerClient(t, env, nil)
+
+	face, err := c.Face()
+	if err != nil {
+		t.Fatalf("Face: %v", err)
+	}
+	prop, ok := face["x"]
+	if !ok {
+		t.Fatal("expected 'x' in Face()")
+	}
+	prop.Set(5.0, "")
+	val, ok := prop.Get("")
+	if !ok || val != 5.0 {
+		t.Fatalf("Face property roundtrip = %v, %v", val, ok)
+	}
+}
@@ -0,0 +1,253 @@
+// Package typed is a generics-based typed façade over microenv.MicroEnv.
+// MicroEnv itself stays dynamic (interface{} in, interface{} out) so it can
+// host properties and functions of any shape; this package lets callers who
+// know the shape up front get compile-time types back without giving up the
+// dynamic core.
+package typed
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/ceil-go/microenv"
+)
+
+// Property is a compile-time-typed view of a single MicroEnv property.
+type Property[T any] struct {
+	env    *microenv.MicroEnv
+	key    string
+	caller string
+}
+
+// Bind validates that env's descriptor declares key with a type matching T
+// (via microenv.SimpleTypeOf) and, if so, returns a Property bound to it.
+func Bind[T any](env *microenv.MicroEnv, key, caller string) (*Property[T], error) {
+	declared, ok := declaredType(env, key)
+	if !ok {
+		return nil, fmt.Errorf("typed: %q is not a declared property", key)
+	}
+	want := microenv.SimpleTypeOf(reflect.TypeOf((*T)(nil)).Elem())
+	if declared != want {
+		return nil, fmt.Errorf("typed: %q is declared %q, not %q", key, declared, want)
+	}
+	return &Property[T]{env: env, key: key, caller: caller}, nil
+}
+
+func declaredType(env *microenv.MicroEnv, key string) (string, bool) {
+	desc := env.Descriptor()
+	children, _ := desc["children"].([]map[string]interface{})
+	for _, c := range children {
+		if k, _ := c["key"].(string); k == key {
+			t, _ := c["type"].(string)
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// Get returns the property's current value, typed as T. The bool result
+// mirrors MicroEnv.Get's ok: false if the key is missing, not allowed for
+// the bound caller, or holds a value whose dynamic type isn't T (checked
+// with a type assertion, so a value of a different but assignable named
+// type also reports false).
+func (p *Property[T]) Get() (T, bool) {
+	var zero T
+	val, _, ok := p.env.Get(p.key, false, p.caller)
+	if !ok {
+		return zero, false
+	}
+	typedVal, ok := val.(T)
+	if !ok {
+		return zero, false
+	}
+	return typedVal, true
+}
+
+// Set stores val, same as MicroEnv.Set.
+func (p *Property[T]) Set(val T) {
+	p.env.Set(p.key, val, p.caller)
+}
+
+// Next blocks until the next Set on the property, or ctx is cancelled.
+func (p *Property[T]) Next(ctx context.Context) (T, error) {
+	var zero T
+	_, ch, ok := p.env.GetCtx(ctx, p.key, true, p.caller)
+	if !ok {
+		return zero, fmt.Errorf("typed: %q is not allowed for this caller", p.key)
+	}
+	select {
+	case val, ok := <-ch:
+		if !ok {
+			return zero, ctx.Err()
+		}
+		typedVal, ok := val.(T)
+		if !ok {
+			return zero, fmt.Errorf("typed: value for %q is not a %T", p.key, zero)
+		}
+		return typedVal, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Watch streams future values of the property, typed as T, until ctx is
+// cancelled. Values that fail the type assertion are silently dropped.
+// Values come from a MicroEnv.Watch subscription, so while the reader of
+// the returned channel falls behind, that subscription's buffer drops its
+// oldest events and those values are never delivered.
+func (p *Property[T]) Watch(ctx context.Context) <-chan T {
+	out := make(chan T)
+	sub, ok := p.env.Watch(p.key, p.caller)
+	if !ok {
+		close(out)
+		return out
+	}
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		for {
+			select {
+			case evt, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				typedVal, ok := evt.NewValue.(T)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- typedVal:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Well-known parameter/return types used to describe the (In, *sync.Map,
+// string) -> (Out..., error) shape CallN verifies against.
+var (
+	dataType   = reflect.TypeOf((*sync.Map)(nil))
+	callerType = reflect.TypeOf("")
+	errType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// verifiedSignature caches its result in env.FacadeCache(), keyed by key,
+// so CallN only inspects a function's shape via reflect once per key
+// rather than on every invocation. Scoping the cache to env (rather than a
+// package-level map keyed by *MicroEnv) means it doesn't outlive env, and
+// comparing the cached reflect.Type against the live one - the same check
+// MicroEnv's own callPlan cache makes - catches the function at key having
+// been swapped for an incompatible one since the last call.
+func verifiedSignature(env *microenv.MicroEnv, key string, wantIn, wantOut []reflect.Type) error {
+	t, ok := env.Signature(key)
+	if !ok {
+		return fmt.Errorf("typed: %q is not a registered function", key)
+	}
+	cache := env.FacadeCache()
+	if cached, ok := cache.Load(key); ok && cached.(reflect.Type) == t {
+		return nil
+	}
+	if t.NumIn() != len(wantIn) {
+		return fmt.Errorf("typed: %q takes %d args, want %d", key, t.NumIn(), len(wantIn))
+	}
+	for i, want := range wantIn {
+		if t.In(i) != want {
+			return fmt.Errorf("typed: %q arg %d is %s, want %s", key, i, t.In(i), want)
+		}
+	}
+	if t.NumOut() != len(wantOut) {
+		return fmt.Errorf("typed: %q returns %d values, want %d", key, t.NumOut(), len(wantOut))
+	}
+	for i, want := range wantOut {
+		if t.Out(i) != want {
+			return fmt.Errorf("typed: %q return %d is %s, want %s", key, i, t.Out(i), want)
+		}
+	}
+	cache.Store(key, t)
+	return nil
+}
+
+// Call1 invokes the function at key with in and returns its single
+// result, typed as Out. The underlying function's signature is verified
+// against (In, *sync.Map, string) -> (Out, error) via reflect the first
+// time a given (env, key) pair is seen, and trusted from the cache after.
+func Call1[In, Out any](env *microenv.MicroEnv, key, caller string, in In) (Out, error) {
+	var zero Out
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	outType := reflect.TypeOf((*Out)(nil)).Elem()
+	if err := verifiedSignature(env, key,
+		[]reflect.Type{inType, dataType, callerType},
+		[]reflect.Type{outType, errType},
+	); err != nil {
+		return zero, err
+	}
+	results, ok := env.Call(key, in, caller)
+	if !ok {
+		return zero, fmt.Errorf("typed: call to %q was refused", key)
+	}
+	out, _ := results[0].(Out)
+	if err, _ := results[1].(error); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// Call2 is Call1 for functions returning two values plus a trailing error.
+func Call2[In, Out1, Out2 any](env *microenv.MicroEnv, key, caller string, in In) (Out1, Out2, error) {
+	var zero1 Out1
+	var zero2 Out2
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	out1Type := reflect.TypeOf((*Out1)(nil)).Elem()
+	out2Type := reflect.TypeOf((*Out2)(nil)).Elem()
+	if err := verifiedSignature(env, key,
+		[]reflect.Type{inType, dataType, callerType},
+		[]reflect.Type{out1Type, out2Type, errType},
+	); err != nil {
+		return zero1, zero2, err
+	}
+	results, ok := env.Call(key, in, caller)
+	if !ok {
+		return zero1, zero2, fmt.Errorf("typed: call to %q was refused", key)
+	}
+	out1, _ := results[0].(Out1)
+	out2, _ := results[1].(Out2)
+	if err, _ := results[2].(error); err != nil {
+		return out1, out2, err
+	}
+	return out1, out2, nil
+}
+
+// Call3 is Call1 for functions returning three values plus a trailing error.
+func Call3[In, Out1, Out2, Out3 any](env *microenv.MicroEnv, key, caller string, in In) (Out1, Out2, Out3, error) {
+	var zero1 Out1
+	var zero2 Out2
+	var zero3 Out3
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	out1Type := reflect.TypeOf((*Out1)(nil)).Elem()
+	out2Type := reflect.TypeOf((*Out2)(nil)).Elem()
+	out3Type := reflect.TypeOf((*Out3)(nil)).Elem()
+	if err := verifiedSignature(env, key,
+		[]reflect.Type{inType, dataType, callerType},
+		[]reflect.Type{out1Type, out2Type, out3Type, errType},
+	); err != nil {
+		return zero1, zero2, zero3, err
+	}
+	results, ok := env.Call(key, in, caller)
+	if !ok {
+		return zero1, zero2, zero3, fmt.Errorf("typed: call to %q was refused", key)
+	}
+	out1, _ := results[0].(Out1)
+	out2, _ := results[1].(Out2)
+	out3, _ := results[2].(Out3)
+	if err, _ := results[3].(error); err != nil {
+		return out1, out2, out3, err
+	}
+	return out1, out2, out3, nil
+}
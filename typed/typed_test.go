@@ -0,0 +1,144 @@
+package typed
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ceil-go/microenv"
+)
+
+func TestBindTypeMismatch(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 123})
+	if _, err := Bind[string](env, "x", ""); err == nil {
+		t.Fatal("expected Bind to reject a string Property over a number property")
+	}
+	if _, err := Bind[int](env, "missing", ""); err == nil {
+		t.Fatal("expected Bind to reject an undeclared key")
+	}
+}
+
+func TestPropertyGetSet(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1})
+	prop, err := Bind[int](env, "x", "")
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	val, ok := prop.Get()
+	if !ok || val != 1 {
+		t.Fatalf("Get() = %v, %v", val, ok)
+	}
+	prop.Set(42)
+	val, ok = prop.Get()
+	if !ok || val != 42 {
+		t.Fatalf("Get() after Set = %v, %v", val, ok)
+	}
+}
+
+func TestPropertyNext(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1})
+	prop, err := Bind[int](env, "x", "")
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	done := make(chan int, 1)
+	go func() {
+		v, err := prop.Next(ctx)
+		if err != nil {
+			t.Errorf("Next: %v", err)
+		}
+		done <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	env.Set("x", 7, "")
+	select {
+	case v := <-done:
+		if v != 7 {
+			t.Errorf("Next() = %d, want 7", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Next")
+	}
+}
+
+func TestPropertyWatch(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1})
+	prop, err := Bind[int](env, "x", "")
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := prop.Watch(ctx)
+
+	env.Set("x", 2, "")
+	env.Set("x", 3, "")
+
+	for _, want := range []int{2, 3} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Errorf("got %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %d", want)
+		}
+	}
+}
+
+func TestCall1(t *testing.T) {
+	sum := func(payload [2]int, data *sync.Map, caller string) (int, error) {
+		return payload[0] + payload[1], nil
+	}
+	env := microenv.NewMicroEnv(map[string]interface{}{"sum": sum})
+
+	got, err := Call1[[2]int, int](env, "sum", "", [2]int{2, 3})
+	if err != nil {
+		t.Fatalf("Call1: %v", err)
+	}
+	if got != 5 {
+		t.Errorf("Call1() = %d, want 5", got)
+	}
+
+	// A second call reuses the cached signature and should still work.
+	got, err = Call1[[2]int, int](env, "sum", "", [2]int{10, 20})
+	if err != nil || got != 30 {
+		t.Errorf("Call1() second call = %d, %v, want 30, nil", got, err)
+	}
+}
+
+func TestCall1SignatureMismatch(t *testing.T) {
+	env := microenv.NewMicroEnv(map[string]interface{}{"x": 1})
+	if _, err := Call1[int, int](env, "x", "", 1); err == nil {
+		t.Fatal("expected Call1 against a non-function key to fail")
+	}
+}
+
+// TestCall1DetectsSignatureSwap guards against a stale cached signature:
+// once "fn" is re-registered with an incompatible shape, the next Call1
+// must re-verify rather than trust the cache from the first call.
+func TestCall1DetectsSignatureSwap(t *testing.T) {
+	sum := func(payload int, data *sync.Map, caller string) (int, error) {
+		return payload * 2, nil
+	}
+	env := microenv.NewMicroEnv(map[string]interface{}{"fn": sum})
+
+	got, err := Call1[int, int](env, "fn", "", 5)
+	if err != nil || got != 10 {
+		t.Fatalf("Call1: %d, %v, want 10, nil", got, err)
+	}
+
+	greet := func(payload string, data *sync.Map, caller string) (string, error) {
+		return "hi " + payload, nil
+	}
+	if err := env.RegisterFunc("fn", greet); err != nil {
+		t.Fatalf("RegisterFunc: %v", err)
+	}
+
+	if _, err := Call1[int, int](env, "fn", "", 5); err == nil {
+		t.Fatal("expected Call1 to reject the swapped-in incompatible signature, not reuse the stale cache")
+	}
+}